@@ -0,0 +1,163 @@
+package i18n
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structField describes one field to carry over when flattening a struct,
+// resolved once per type and then reused.
+type structField struct {
+	name      string
+	index     []int
+	anonymous bool
+}
+
+// structFieldCache memoizes the reflected field layout of a struct type,
+// keyed by reflect.Type, so repeated translations against the same data
+// type don't re-walk its fields.
+var structFieldCache sync.Map // map[reflect.Type][]structField
+
+// toMap converts input into a map[string]interface{} suitable for use as
+// text/template data. Maps are converted recursively; structs are
+// flattened recursively, honoring the "i18n" tag (falling back to "json",
+// then the field name) and unwrapping embedded structs into their
+// parent's map. A top-level map[string]interface{} is returned as-is
+// without walking it again.
+func toMap(input interface{}) map[string]interface{} {
+	if input == nil {
+		return nil
+	}
+	if data, ok := input.(map[string]interface{}); ok {
+		return data
+	}
+	return toMapValue(reflect.ValueOf(input))
+}
+
+func toMapValue(v reflect.Value) map[string]interface{} {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return structToMap(v)
+	case reflect.Map:
+		return mapToMap(v)
+	default:
+		return nil
+	}
+}
+
+func mapToMap(v reflect.Value) map[string]interface{} {
+	out := make(map[string]interface{}, v.Len())
+	for _, key := range v.MapKeys() {
+		out[fmt.Sprint(key.Interface())] = toValue(v.MapIndex(key))
+	}
+	return out
+}
+
+// structToMap converts the exported fields of a struct value to a
+// map[string]interface{}, recursing into nested structs, maps, and
+// slices of either.
+//
+// Code inspired by github.com/fatih/structs.
+func structToMap(v reflect.Value) map[string]interface{} {
+	fields := cachedStructFields(v.Type())
+	out := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		fv := v.FieldByIndex(f.index)
+		if f.anonymous {
+			for k, val := range toMapValue(fv) {
+				out[k] = val
+			}
+			continue
+		}
+		out[f.name] = toValue(fv)
+	}
+	return out
+}
+
+// toValue converts a single field or map value for inclusion in the
+// result, recursing into nested structs, maps, and slices of either so
+// that paths like "{{.User.Name}}" resolve at any depth.
+func toValue(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return structToMap(v)
+	case reflect.Map:
+		return mapToMap(v)
+	case reflect.Slice, reflect.Array:
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = toValue(v.Index(i))
+		}
+		return out
+	default:
+		return v.Interface()
+	}
+}
+
+func cachedStructFields(t reflect.Type) []structField {
+	if cached, ok := structFieldCache.Load(t); ok {
+		return cached.([]structField)
+	}
+
+	fields := make([]structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			// skip unexported field
+			continue
+		}
+		name, tagged := fieldTag(field)
+		if tagged && name == "" {
+			// tag == "-": field is explicitly excluded
+			continue
+		}
+		fields = append(fields, structField{
+			name:      name,
+			index:     field.Index,
+			anonymous: field.Anonymous && !tagged,
+		})
+	}
+
+	actual, _ := structFieldCache.LoadOrStore(t, fields)
+	return actual.([]structField)
+}
+
+// fieldTag resolves a struct field's map key, preferring the "i18n" tag,
+// then falling back to "json", then the Go field name. tagged reports
+// whether a tag supplied an explicit name (a tag such as ",omitempty"
+// with an empty name counts as untagged), so an untagged embedded struct can
+// still be unwrapped rather than nested under its type name. A tag of
+// "-" (from either "i18n" or "json") reports name == "" with tagged ==
+// true, telling the caller to skip the field entirely, mirroring
+// encoding/json's own "-" semantics.
+func fieldTag(field reflect.StructField) (name string, tagged bool) {
+	tag := field.Tag.Get("i18n")
+	if tag == "" {
+		tag = field.Tag.Get("json")
+	}
+	if tag == "" {
+		return field.Name, false
+	}
+	name = strings.Split(tag, ",")[0]
+	if name == "-" {
+		return "", true
+	}
+	if name == "" {
+		return field.Name, false
+	}
+	return name, true
+}
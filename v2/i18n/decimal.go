@@ -0,0 +1,25 @@
+package i18n
+
+import "strconv"
+
+// Decimal wraps a numeric plural count to preserve the visible precision
+// of its original representation. Go's float64 alone can't distinguish
+// "1" from "1.0", but CLDR plural rules care about that distinction: the
+// v, w, f, and t operands are derived from the digits actually shown, so
+// French treats 1.5 as "one" but 2.0 as "other". Pass a Decimal instead of
+// a bare float to Translate when that distinction matters.
+type Decimal struct {
+	Value     float64
+	Precision int
+}
+
+// NewDecimal returns a Decimal that renders value with precision digits
+// after the decimal point, e.g. NewDecimal(1, 1) represents "1.0".
+func NewDecimal(value float64, precision int) Decimal {
+	return Decimal{Value: value, Precision: precision}
+}
+
+// String formats the decimal, preserving its configured precision.
+func (d Decimal) String() string {
+	return strconv.FormatFloat(d.Value, 'f', d.Precision, 64)
+}
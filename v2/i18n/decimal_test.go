@@ -0,0 +1,50 @@
+package i18n
+
+import "testing"
+
+func TestIsNumber(t *testing.T) {
+	tests := []struct {
+		name string
+		n    interface{}
+		want bool
+	}{
+		{"int", int(1), true},
+		{"int8", int8(1), true},
+		{"int64", int64(1), true},
+		{"uint", uint(1), true},
+		{"uint64", uint64(1), true},
+		{"float32", float32(1.5), true},
+		{"float64", float64(1.5), true},
+		{"string", "1", true},
+		{"decimal", NewDecimal(1, 1), true},
+		{"map", map[string]interface{}{"Count": 1}, false},
+		{"struct", struct{}{}, false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNumber(tt.n); got != tt.want {
+				t.Errorf("isNumber(%#v) = %v, want %v", tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalString(t *testing.T) {
+	tests := []struct {
+		d    Decimal
+		want string
+	}{
+		{NewDecimal(1, 0), "1"},
+		{NewDecimal(1, 1), "1.0"},
+		{NewDecimal(1.5, 1), "1.5"},
+		{NewDecimal(2, 1), "2.0"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.d.String(); got != tt.want {
+			t.Errorf("Decimal{%v, %d}.String() = %q, want %q", tt.d.Value, tt.d.Precision, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package i18n
+
+import "fmt"
+
+// MissingTranslationError indicates that no translation for ID was found
+// in any of the translator's preferred languages.
+type MissingTranslationError struct {
+	ID           string
+	LanguageTags []string
+}
+
+func (e *MissingTranslationError) Error() string {
+	return fmt.Sprintf("i18n: no translation for %q in languages %v", e.ID, e.LanguageTags)
+}
+
+// PluralRuleError wraps the error returned while resolving a plural form
+// for ID in LangTag, so callers can distinguish a malformed plural count
+// from a missing translation.
+type PluralRuleError struct {
+	ID      string
+	LangTag string
+	Err     error
+}
+
+func (e *PluralRuleError) Error() string {
+	return fmt.Sprintf("i18n: plural rule error for %q (%s): %s", e.ID, e.LangTag, e.Err)
+}
+
+func (e *PluralRuleError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,333 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Message is a parsed ICU MessageFormat-style pattern, compiled once and
+// safe to render repeatedly.
+type Message struct {
+	nodes []msgNode
+}
+
+// Render executes the message against args, using pluralRule to resolve
+// any plural sub-patterns the message contains.
+func (m Message) Render(args map[string]interface{}, pluralRule *PluralRule) (string, error) {
+	var b strings.Builder
+	if err := m.render(&b, args, pluralRule); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func (m Message) render(b *strings.Builder, args map[string]interface{}, pluralRule *PluralRule) error {
+	for _, node := range m.nodes {
+		if err := node.render(b, args, pluralRule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type msgNode interface {
+	render(b *strings.Builder, args map[string]interface{}, pluralRule *PluralRule) error
+}
+
+type textNode string
+
+func (n textNode) render(b *strings.Builder, args map[string]interface{}, pluralRule *PluralRule) error {
+	b.WriteString(string(n))
+	return nil
+}
+
+// argNode renders a named placeholder, e.g. "{name}".
+type argNode struct {
+	name string
+}
+
+func (n argNode) render(b *strings.Builder, args map[string]interface{}, pluralRule *PluralRule) error {
+	v, ok := args[n.name]
+	if !ok {
+		return fmt.Errorf("i18n: missing argument %q", n.name)
+	}
+	fmt.Fprint(b, v)
+	return nil
+}
+
+// countNode renders "#" as the value of the enclosing plural argument.
+type countNode struct {
+	arg string
+}
+
+func (n countNode) render(b *strings.Builder, args map[string]interface{}, pluralRule *PluralRule) error {
+	v, ok := args[n.arg]
+	if !ok {
+		return fmt.Errorf("i18n: missing argument %q", n.arg)
+	}
+	fmt.Fprint(b, v)
+	return nil
+}
+
+// pluralNode renders one of its cases based on the CLDR plural form of
+// args[arg], as resolved by pluralRule. An exact-value case (e.g. "=0")
+// takes precedence over the matched form; "other" is the required
+// fallback.
+type pluralNode struct {
+	arg   string
+	cases map[string]Message
+}
+
+func (n pluralNode) render(b *strings.Builder, args map[string]interface{}, pluralRule *PluralRule) error {
+	count, ok := args[n.arg]
+	if !ok {
+		return fmt.Errorf("i18n: missing plural argument %q", n.arg)
+	}
+	if exact, ok := n.cases["="+fmt.Sprint(count)]; ok {
+		return exact.render(b, args, pluralRule)
+	}
+	if pluralRule == nil {
+		return fmt.Errorf("i18n: no plural rule available to resolve %q", n.arg)
+	}
+	form, err := pluralRule.PluralForm(count)
+	if err != nil {
+		return err
+	}
+	msg, ok := n.cases[fmt.Sprint(form)]
+	if !ok {
+		msg, ok = n.cases["other"]
+		if !ok {
+			return fmt.Errorf("i18n: plural pattern for %q has no \"other\" case", n.arg)
+		}
+	}
+	return msg.render(b, args, pluralRule)
+}
+
+// selectNode renders one of its cases based on the string value of
+// args[arg], e.g. {gender, select, male {...} female {...} other {...}}.
+type selectNode struct {
+	arg   string
+	cases map[string]Message
+}
+
+func (n selectNode) render(b *strings.Builder, args map[string]interface{}, pluralRule *PluralRule) error {
+	value, ok := args[n.arg]
+	if !ok {
+		return fmt.Errorf("i18n: missing select argument %q", n.arg)
+	}
+	msg, ok := n.cases[fmt.Sprint(value)]
+	if !ok {
+		msg, ok = n.cases["other"]
+		if !ok {
+			return fmt.Errorf("i18n: select pattern for %q has no \"other\" case", n.arg)
+		}
+	}
+	return msg.render(b, args, pluralRule)
+}
+
+// ParseMessage parses an ICU MessageFormat-style pattern - named
+// placeholders, plural, and select - into a Message that can be rendered
+// repeatedly without re-parsing.
+func ParseMessage(pattern string) (Message, error) {
+	p := &messageParser{input: pattern}
+	nodes, err := p.parseNodes(false)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{nodes: nodes}, nil
+}
+
+type messageParser struct {
+	input          string
+	pos            int
+	pluralArgStack []string
+}
+
+// parseNodes parses pattern text until the input is exhausted, or, if
+// stopAtBrace is true, until the matching closing brace of a case body.
+func (p *messageParser) parseNodes(stopAtBrace bool) ([]msgNode, error) {
+	var nodes []msgNode
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, textNode(text.String()))
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.input) {
+		switch c := p.input[p.pos]; {
+		case c == '{':
+			flush()
+			node, err := p.parseArgument()
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		case c == '}' && stopAtBrace:
+			flush()
+			p.pos++
+			return nodes, nil
+		case c == '#' && len(p.pluralArgStack) > 0:
+			flush()
+			nodes = append(nodes, countNode{arg: p.pluralArgStack[len(p.pluralArgStack)-1]})
+			p.pos++
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+	if stopAtBrace {
+		return nil, fmt.Errorf("i18n: unterminated message pattern, expected '}'")
+	}
+	flush()
+	return nodes, nil
+}
+
+func (p *messageParser) parseArgument() (msgNode, error) {
+	p.pos++ // consume '{'
+	name := p.parseToken()
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return nil, fmt.Errorf("i18n: unterminated argument %q", name)
+	}
+	if p.input[p.pos] == '}' {
+		p.pos++
+		return argNode{name: name}, nil
+	}
+	if p.input[p.pos] != ',' {
+		return nil, fmt.Errorf("i18n: malformed argument %q", name)
+	}
+	p.pos++ // consume ','
+	p.skipSpace()
+	kind := p.parseToken()
+	p.skipSpace()
+	if p.pos >= len(p.input) || p.input[p.pos] != ',' {
+		return nil, fmt.Errorf("i18n: malformed argument %q: expected ',' after %q", name, kind)
+	}
+	p.pos++ // consume ','
+	p.skipSpace()
+	switch kind {
+	case "plural":
+		return p.parseCases(name, true)
+	case "select":
+		return p.parseCases(name, false)
+	default:
+		return nil, fmt.Errorf("i18n: unsupported argument type %q for %q", kind, name)
+	}
+}
+
+func (p *messageParser) parseCases(arg string, isPlural bool) (msgNode, error) {
+	if isPlural {
+		p.pluralArgStack = append(p.pluralArgStack, arg)
+		defer func() { p.pluralArgStack = p.pluralArgStack[:len(p.pluralArgStack)-1] }()
+	}
+
+	cases := make(map[string]Message)
+	for {
+		p.skipSpace()
+		if p.pos < len(p.input) && p.input[p.pos] == '}' {
+			p.pos++
+			break
+		}
+		if p.pos >= len(p.input) {
+			return nil, fmt.Errorf("i18n: unterminated cases for %q", arg)
+		}
+		key := p.parseToken()
+		p.skipSpace()
+		if p.pos >= len(p.input) || p.input[p.pos] != '{' {
+			return nil, fmt.Errorf("i18n: expected '{' after case %q in %q", key, arg)
+		}
+		p.pos++ // consume '{'
+		nodes, err := p.parseNodes(true)
+		if err != nil {
+			return nil, err
+		}
+		cases[key] = Message{nodes: nodes}
+	}
+
+	if isPlural {
+		return pluralNode{arg: arg, cases: cases}, nil
+	}
+	return selectNode{arg: arg, cases: cases}, nil
+}
+
+// parseToken reads an identifier or case key: a run of characters up to
+// the next delimiter or whitespace.
+func (p *messageParser) parseToken() string {
+	start := p.pos
+	for p.pos < len(p.input) {
+		switch c := p.input[p.pos]; {
+		case c == ',' || c == '{' || c == '}':
+			return p.input[start:p.pos]
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			return p.input[start:p.pos]
+		}
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *messageParser) skipSpace() {
+	for p.pos < len(p.input) {
+		switch p.input[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// messageCache holds compiled Messages keyed by bundle, language tag, and
+// message id, so a pattern is parsed into an AST on its first use and the
+// compiled Message is reused after that (concurrent first uses may each
+// parse it before one is stored). Keying on the bundle pointer (rather than just langTag/id) keeps
+// two bundles that happen to share a language tag and message id - e.g.
+// two tests, or a bundle reloaded with edited translations - from
+// colliding on and serving each other's stale compiled Message.
+var messageCache sync.Map // map[messageCacheKey]Message
+
+type messageCacheKey struct {
+	bundle  *Bundle
+	langTag string
+	id      string
+}
+
+// TranslateMessage renders the ICU MessageFormat-style pattern registered
+// under id for the first of the translator's LanguageTags that has one
+// (MatchedTag is not consulted), substituting args
+// by name and resolving any plural sub-pattern via the bundle's
+// PluralRule for that language.
+func (t *Translator) TranslateMessage(id string, args map[string]interface{}) (string, error) {
+	for _, langTag := range t.LanguageTags {
+		patterns := t.Bundle.Messages[langTag]
+		if patterns == nil {
+			continue
+		}
+		pattern, ok := patterns[id]
+		if !ok {
+			continue
+		}
+		msg, err := compiledMessage(t.Bundle, langTag, id, pattern)
+		if err != nil {
+			return "", err
+		}
+		return msg.Render(args, t.Bundle.PluralRules[langTag])
+	}
+	return "", &MissingTranslationError{ID: id, LanguageTags: t.LanguageTags}
+}
+
+func compiledMessage(bundle *Bundle, langTag, id, pattern string) (Message, error) {
+	key := messageCacheKey{bundle: bundle, langTag: langTag, id: id}
+	if cached, ok := messageCache.Load(key); ok {
+		return cached.(Message), nil
+	}
+	msg, err := ParseMessage(pattern)
+	if err != nil {
+		return Message{}, err
+	}
+	messageCache.Store(key, msg)
+	return msg, nil
+}
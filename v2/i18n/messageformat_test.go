@@ -0,0 +1,79 @@
+package i18n
+
+import "testing"
+
+func TestMessageRenderArgument(t *testing.T) {
+	msg, err := ParseMessage("Hello, {name}!")
+	if err != nil {
+		t.Fatalf("ParseMessage: %v", err)
+	}
+	got, err := msg.Render(map[string]interface{}{"name": "World"}, nil)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if want := "Hello, World!"; got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+}
+
+func TestMessageRenderSelect(t *testing.T) {
+	msg, err := ParseMessage("{gender, select, male {He} female {She} other {They}} liked this.")
+	if err != nil {
+		t.Fatalf("ParseMessage: %v", err)
+	}
+
+	for gender, want := range map[string]string{
+		"male":   "He liked this.",
+		"female": "She liked this.",
+		"other":  "They liked this.",
+		"alien":  "They liked this.", // falls back to "other"
+	} {
+		got, err := msg.Render(map[string]interface{}{"gender": gender}, nil)
+		if err != nil {
+			t.Fatalf("Render(%q): %v", gender, err)
+		}
+		if got != want {
+			t.Errorf("Render(%q) = %q, want %q", gender, got, want)
+		}
+	}
+}
+
+func TestMessageRenderPluralExactMatch(t *testing.T) {
+	// An "=N" exact case is matched before the plural rule is consulted,
+	// so this must work even with a nil pluralRule.
+	msg, err := ParseMessage("{count, plural, =0 {no items} one {# item} other {# items}}")
+	if err != nil {
+		t.Fatalf("ParseMessage: %v", err)
+	}
+	got, err := msg.Render(map[string]interface{}{"count": 0}, nil)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if want := "no items"; got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+}
+
+func TestMessageRenderPluralWithoutExactMatchNeedsPluralRule(t *testing.T) {
+	msg, err := ParseMessage("{count, plural, one {# item} other {# items}}")
+	if err != nil {
+		t.Fatalf("ParseMessage: %v", err)
+	}
+	_, err = msg.Render(map[string]interface{}{"count": 2}, nil)
+	if err == nil {
+		t.Fatal("Render with nil pluralRule and no exact case: want error, got nil")
+	}
+}
+
+func TestParseMessageErrors(t *testing.T) {
+	tests := []string{
+		"{unterminated",
+		"{count, plural, one {x}", // missing closing brace for the plural block
+		"{name, unsupported}",
+	}
+	for _, pattern := range tests {
+		if _, err := ParseMessage(pattern); err == nil {
+			t.Errorf("ParseMessage(%q): want error, got nil", pattern)
+		}
+	}
+}
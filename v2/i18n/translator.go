@@ -4,40 +4,157 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode/utf8"
+
+	"golang.org/x/text/language"
 )
 
-// LanguageTagRegex Matches language tags like en-US, and zh-Hans-CN.
-// Language tags are case-insensitive.
-var LanguageTagRegex = regexp.MustCompile(`[a-zA-Z]{2,}([\-_][a-zA-Z]{2,})+`)
+// languageRangeRegex matches a single Accept-Language range, e.g. "en-US"
+// or the wildcard "*". It matches the bare range only; any ";q=" weight
+// is split off before the range is checked against it.
+var languageRangeRegex = regexp.MustCompile(`^[a-zA-Z]{1,8}(?:[\-_][a-zA-Z0-9]{1,8})*$|^\*$`)
 
 // Translator translates messages.
 type Translator struct {
 	Bundle       *Bundle
 	LanguageTags []string
+
+	// MatchedTag is the canonical BCP 47 tag chosen by matching the
+	// parsed Accept-Language ranges against the bundle's registered tags via
+	// golang.org/x/text/language. It is empty if no registered tag
+	// matched.
+	MatchedTag string
+}
+
+// languageRange is a single Accept-Language range together with its
+// parsed q weight.
+type languageRange struct {
+	tag    string
+	weight float64
 }
 
 // NewTranslator returns a translator that looks up translations
 // in the bundle according to the order of language tags found in preferences.
 //
-// It can parse languages from Accept-Language headers (RFC 2616),
-// but it assumes weights are monotonically decreasing.
+// It parses languages from Accept-Language headers per RFC 7231: ranges are
+// sorted by descending q weight (ties keep their original order), and
+// ranges with q=0 are dropped. It also matches the parsed ranges against
+// the bundle's registered tags using golang.org/x/text/language, handling
+// script/region fallback (e.g. zh-Hant -> zh-TW) and macrolanguage
+// relations; the result is exposed as MatchedTag.
 func NewTranslator(bundle *Bundle, prefs string) *Translator {
 	translator := &Translator{
 		Bundle:       bundle,
 		LanguageTags: []string{},
 	}
 
-	langTags := LanguageTagRegex.FindAllString(prefs, -1)
+	ranges := parseAcceptLanguage(prefs)
 	var tags []string
-	for _, langTag := range langTags {
-		tags = append(tags, expandTag(langTag)...)
+	for _, r := range ranges {
+		tags = append(tags, expandTag(r.tag)...)
 	}
 	translator.LanguageTags = dedupe(tags)
+	translator.MatchedTag = matchBundleTag(bundle, ranges)
 	return translator
 }
 
+// parseAcceptLanguage tokenizes an Accept-Language header value into its
+// language ranges, parses each range's q weight (default 1.0), drops
+// ranges with q=0, and stable-sorts the remainder by descending weight.
+func parseAcceptLanguage(prefs string) []languageRange {
+	var ranges []languageRange
+	for _, part := range strings.Split(prefs, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if !languageRangeRegex.MatchString(tag) {
+			continue
+		}
+
+		weight := 1.0
+		for _, field := range fields[1:] {
+			field = strings.TrimSpace(field)
+			name, value, found := strings.Cut(field, "=")
+			if !found || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			w, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			weight = w
+		}
+		if weight <= 0 {
+			continue
+		}
+
+		ranges = append(ranges, languageRange{tag: tag, weight: weight})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].weight > ranges[j].weight
+	})
+	return ranges
+}
+
+// matchBundleTag picks the best matching tag registered in the bundle for
+// the given ranges, using golang.org/x/text/language so that script,
+// region, and macrolanguage fallback are handled correctly instead of the
+// naive suffix-trimming expandTag performs for the raw tag list.
+func matchBundleTag(bundle *Bundle, ranges []languageRange) string {
+	if bundle == nil || len(ranges) == 0 {
+		return ""
+	}
+
+	langTags := make([]string, 0, len(bundle.Translations))
+	for langTag := range bundle.Translations {
+		langTags = append(langTags, langTag)
+	}
+	// bundle.Translations is a map, so iteration order is randomized per
+	// run; language.NewMatcher breaks confidence ties in favor of
+	// whichever supported tag was passed in first, so without a
+	// deterministic order MatchedTag could flip between runs.
+	sort.Strings(langTags)
+
+	var supported []language.Tag
+	for _, langTag := range langTags {
+		tag, err := language.Parse(langTag)
+		if err != nil {
+			continue
+		}
+		supported = append(supported, tag)
+	}
+	if len(supported) == 0 {
+		return ""
+	}
+
+	var preferred []language.Tag
+	for _, r := range ranges {
+		tag, err := language.Parse(r.tag)
+		if err != nil {
+			continue
+		}
+		preferred = append(preferred, tag)
+	}
+	if len(preferred) == 0 {
+		return ""
+	}
+
+	matcher := language.NewMatcher(supported)
+	tag, _, confidence := matcher.Match(preferred...)
+	if confidence == language.No {
+		return ""
+	}
+	return tag.String()
+}
+
 func expandTag(tag string) []string {
 	tag = strings.TrimSpace(tag)
 	tag = strings.ToLower(tag)
@@ -66,7 +183,64 @@ func dedupe(strs []string) []string {
 
 // Translate iterates through language tags to find the first non-empty translation in the bundle.
 // It returns the default translation if no other translation is found.
+// If the bundle's MissingTranslationPlaceholder is set, it returns "[[id]]"
+// in place of the default translation.
+//
+// To detect missing translations or malformed plural rules
+// programmatically instead of relying on this best-effort string, use
+// TranslateE.
 func (t *Translator) Translate(id, defaultTranslation string, args ...interface{}) string {
+	translated, err := t.translate(id, args)
+	if err == nil {
+		return translated
+	}
+
+	if pluralErr, ok := err.(*PluralRuleError); ok {
+		if t.Bundle.OnError != nil {
+			t.Bundle.OnError(pluralErr.LangTag, id, pluralErr)
+		}
+		return fmt.Sprintf("[ERR][%s] %s", id, pluralErr.Err.Error())
+	}
+
+	if t.Bundle.OnMissing != nil {
+		t.Bundle.OnMissing(firstLanguageTag(t.LanguageTags), id)
+	}
+	if t.Bundle.MissingTranslationPlaceholder {
+		return fmt.Sprintf("[[%s]]", id)
+	}
+	return defaultTranslation
+}
+
+// TranslateE behaves like Translate, but returns a typed error - a
+// *MissingTranslationError or *PluralRuleError - instead of baking the
+// failure into the returned string, so callers can detect and log
+// untranslated ids during development.
+func (t *Translator) TranslateE(id, defaultTranslation string, args ...interface{}) (string, error) {
+	translated, err := t.translate(id, args)
+	if err == nil {
+		return translated, nil
+	}
+
+	if pluralErr, ok := err.(*PluralRuleError); ok {
+		if t.Bundle.OnError != nil {
+			t.Bundle.OnError(pluralErr.LangTag, id, pluralErr)
+		}
+		return defaultTranslation, err
+	}
+
+	if t.Bundle.OnMissing != nil {
+		t.Bundle.OnMissing(firstLanguageTag(t.LanguageTags), id)
+	}
+	if t.Bundle.MissingTranslationPlaceholder {
+		return fmt.Sprintf("[[%s]]", id), err
+	}
+	return defaultTranslation, err
+}
+
+// translate resolves id against the translator's language tags, returning
+// either the translated string or the *MissingTranslationError /
+// *PluralRuleError describing why it couldn't.
+func (t *Translator) translate(id string, args []interface{}) (string, error) {
 	if len(args) > 2 {
 		panic("too many args passed to Localize")
 	}
@@ -86,15 +260,24 @@ func (t *Translator) Translate(id, defaultTranslation string, args ...interface{
 		pluralCount, data := parseArgs(args)
 		pluralForm, err := pluralRule.PluralForm(pluralCount)
 		if err != nil {
-			return fmt.Sprintf("[ERR][%s] %s", id, err.Error())
+			return "", &PluralRuleError{ID: id, LangTag: langTag, Err: err}
 		}
 		translated := translation.Translate(pluralForm, data)
 		if translated == "" {
 			continue
 		}
-		return translated
+		return translated, nil
 	}
-	return defaultTranslation
+	return "", &MissingTranslationError{ID: id, LanguageTags: t.LanguageTags}
+}
+
+// firstLanguageTag returns the translator's most-preferred language tag,
+// or "" if it has none, for reporting in OnMissing.
+func firstLanguageTag(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return tags[0]
 }
 
 func parseArgs(args []interface{}) (count interface{}, data interface{}) {
@@ -126,41 +309,22 @@ func parseArgs(args []interface{}) (count interface{}, data interface{}) {
 	return
 }
 
+// isNumber reports whether n should be treated as a plural count rather
+// than template data. Besides string (an already-formatted count) and
+// Decimal (a count with explicit visible precision), it classifies by
+// reflect.Kind so any integer or floating-point type - including uint and
+// float32/float64 - is recognized, not just the signed int family.
 func isNumber(n interface{}) bool {
 	switch n.(type) {
-	case int, int8, int16, int32, int64, string:
+	case string, Decimal:
 		return true
 	}
-	return false
-}
-
-func toMap(input interface{}) map[string]interface{} {
-	if data, ok := input.(map[string]interface{}); ok {
-		return data
-	}
-	v := reflect.ValueOf(input)
-	switch v.Kind() {
-	case reflect.Ptr:
-		return toMap(v.Elem().Interface())
-	case reflect.Struct:
-		return structToMap(v)
-	default:
-		return nil
+	switch reflect.ValueOf(n).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
 	}
+	return false
 }
 
-// Converts the top level of a struct to a map[string]interface{}.
-// Code inspired by github.com/fatih/structs.
-func structToMap(v reflect.Value) map[string]interface{} {
-	out := make(map[string]interface{})
-	t := v.Type()
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		if field.PkgPath != "" {
-			// skip unexported field
-			continue
-		}
-		out[field.Name] = v.FieldByName(field.Name).Interface()
-	}
-	return out
-}
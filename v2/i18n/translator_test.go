@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name  string
+		prefs string
+		want  []string // expected tags, in order
+	}{
+		{
+			name:  "out of order weights are sorted by descending q",
+			prefs: "en;q=0.5, fr;q=0.9",
+			want:  []string{"fr", "en"},
+		},
+		{
+			name:  "default weight is 1.0",
+			prefs: "en-US, fr;q=0.9",
+			want:  []string{"en-US", "fr"},
+		},
+		{
+			name:  "q=0 ranges are dropped",
+			prefs: "en;q=0, fr;q=0.5",
+			want:  []string{"fr"},
+		},
+		{
+			name:  "equal weights keep their original order (stable sort)",
+			prefs: "en;q=0.8, de;q=0.8, fr;q=0.8",
+			want:  []string{"en", "de", "fr"},
+		},
+		{
+			name:  "malformed q value falls back to default weight",
+			prefs: "en;q=bogus, fr;q=0.9",
+			want:  []string{"en", "fr"},
+		},
+		{
+			name:  "whitespace around ranges and weights is trimmed",
+			prefs: " en ; q=0.5 ,  fr ",
+			want:  []string{"fr", "en"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges := parseAcceptLanguage(tt.prefs)
+			var got []string
+			for _, r := range ranges {
+				got = append(got, r.tag)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAcceptLanguage(%q) tags = %v, want %v", tt.prefs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTranslatorLanguageTags(t *testing.T) {
+	translator := NewTranslator(nil, "en-US, fr;q=0.5")
+	want := []string{"en-us", "en", "fr"}
+	if !reflect.DeepEqual(translator.LanguageTags, want) {
+		t.Errorf("LanguageTags = %v, want %v", translator.LanguageTags, want)
+	}
+	if translator.MatchedTag != "" {
+		t.Errorf("MatchedTag = %q, want empty for a nil bundle", translator.MatchedTag)
+	}
+}